@@ -0,0 +1,114 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// BatchEncoder streams a JSON-RPC batch array to an io.Writer one element
+// at a time, instead of building the whole array in memory with repeated
+// string concatenation. Batch is a thin wrapper over it that writes into a
+// strings.Builder. Useful for large batches (thousands of calls), which are
+// common with bulk, ethereum-style queries.
+type BatchEncoder struct {
+	w       io.Writer
+	started bool
+	closed  bool
+	err     error
+}
+
+// NewBatchEncoder returns a BatchEncoder that writes a batch array to w.
+func NewBatchEncoder(w io.Writer) *BatchEncoder {
+	return &BatchEncoder{w: w}
+}
+
+// Append writes rpc, the output of Request/Notification/Success/Error,
+// as the next element of the batch.
+func (e *BatchEncoder) Append(rpc string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if !e.started {
+		e.started = true
+		_, e.err = io.WriteString(e.w, "[")
+	} else {
+		_, e.err = io.WriteString(e.w, ",")
+	}
+	if e.err == nil {
+		_, e.err = io.WriteString(e.w, rpc)
+	}
+	return e.err
+}
+
+// Close writes the batch's closing "]", opening it first with no
+// elements if Append was never called so an empty batch still reads back
+// as "[]". It returns the first error Append or Close ran into, and is
+// safe to call more than once.
+func (e *BatchEncoder) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+	if e.err != nil {
+		return e.err
+	}
+	if !e.started {
+		if _, e.err = io.WriteString(e.w, "["); e.err != nil {
+			return e.err
+		}
+	}
+	_, e.err = io.WriteString(e.w, "]")
+	return e.err
+}
+
+// BatchDecoder streams the elements of a JSON-RPC batch array from an
+// io.Reader one at a time via json.Decoder.Token, instead of unmarshalling
+// the whole array into a []*RPC up front. Parse reads batch arrays through
+// it and collects the elements into a slice.
+type BatchDecoder struct {
+	dec    *json.Decoder
+	opened bool
+	closed bool
+}
+
+// NewBatchDecoder returns a BatchDecoder reading a batch array from r.
+func NewBatchDecoder(r io.Reader) *BatchDecoder {
+	return &BatchDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next decodes and returns the next element of the batch, running it
+// through the same parsing Parse applies to a single message. It returns
+// io.EOF once the closing "]" is reached. Unlike Parse, a malformed
+// element aborts the stream rather than producing a single InvalidType
+// element, since a streaming decoder can't resynchronize mid-array.
+func (d *BatchDecoder) Next() (*RPC, error) {
+	if d.closed {
+		return nil, io.EOF
+	}
+	if !d.opened {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, errors.New("jsonrpc: batch must be a JSON array")
+		}
+		d.opened = true
+	}
+	if !d.dec.More() {
+		d.closed = true
+		_, err := d.dec.Token() // consume the closing ']'
+		if err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	req := &RPC{}
+	if err := d.dec.Decode(req); err != nil {
+		d.closed = true
+		return nil, err
+	}
+	parse(req)
+	return req, nil
+}
@@ -0,0 +1,162 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchEncoder(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf strings.Builder
+	enc := NewBatchEncoder(&buf)
+	assert.Nil(enc.Close())
+	assert.Equal("[]", buf.String())
+
+	buf.Reset()
+	enc = NewBatchEncoder(&buf)
+	assert.Nil(enc.Append(`{"jsonrpc":"2.0","method":"update","id":1}`))
+	assert.Nil(enc.Append(`{"jsonrpc":"2.0","method":"update","id":2}`))
+	assert.Nil(enc.Close())
+	assert.Equal(`[{"jsonrpc":"2.0","method":"update","id":1},{"jsonrpc":"2.0","method":"update","id":2}]`, buf.String())
+	assert.Nil(enc.Close()) // idempotent
+}
+
+func TestBatchDecoder(t *testing.T) {
+	assert := assert.New(t)
+
+	dec := NewBatchDecoder(strings.NewReader("[]"))
+	_, err := dec.Next()
+	assert.Equal(io.EOF, err)
+
+	arr := `[
+		{"jsonrpc": "2.0", "method": "sum", "params": [1,2,4], "id": "1"},
+		{"jsonrpc": "2.0", "method": "notify_hello", "params": [7]},
+		{"jsonrpc": "2.0", "result": 19, "id": "2"}
+	]`
+	dec = NewBatchDecoder(strings.NewReader(arr))
+	var got []*RPC
+	for {
+		r, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+		got = append(got, r)
+	}
+	if assert.Equal(3, len(got)) {
+		assert.Equal(RequestType, got[0].Type)
+		assert.Equal("1", got[0].ID.String())
+		assert.Equal(NotificationType, got[1].Type)
+		assert.Equal(SuccessType, got[2].Type)
+	}
+
+	dec = NewBatchDecoder(strings.NewReader(`[x:x]`))
+	_, err = dec.Next()
+	assert.NotNil(err)
+	assert.NotEqual(io.EOF, err)
+
+	dec = NewBatchDecoder(strings.NewReader(`{"not": "an array"}`))
+	_, err = dec.Next()
+	assert.NotNil(err)
+}
+
+func buildBatchMsgs(n int) []string {
+	msgs := make([]string, n)
+	for i := 0; i < n; i++ {
+		msg, _ := Request(i, "update", i)
+		msgs[i] = msg
+	}
+	return msgs
+}
+
+// oldBatchConcat is the O(n) string-concatenation Batch this package used
+// before BatchEncoder, kept only so BenchmarkBatchEncodeConcat has
+// something independent to compare the streaming encoder against.
+func oldBatchConcat(batch ...string) string {
+	if len(batch) == 0 {
+		return "[]"
+	}
+	arrstr := "["
+	for index := 0; index < len(batch)-1; index++ {
+		arrstr += batch[index]
+		arrstr += ","
+	}
+	arrstr += batch[len(batch)-1]
+	arrstr += "]"
+	return arrstr
+}
+
+// oldParseBatchUpfront is the full-in-memory-decode Parse this package
+// used before BatchDecoder, kept only so BenchmarkBatchParseUpfront has
+// something independent to compare the streaming decoder against.
+func oldParseBatchUpfront(msg string) []*RPC {
+	batch := make([]*RPC, 1)
+	if err := validateMsg(msg, &batch); err == nil {
+		for _, val := range batch {
+			parse(val)
+		}
+	} else {
+		batch[0] = &RPC{Error: err, Type: InvalidType}
+	}
+	return batch
+}
+
+func BenchmarkBatchEncodeConcat(b *testing.B) {
+	msgs := buildBatchMsgs(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		oldBatchConcat(msgs...)
+	}
+}
+
+func BenchmarkBatchEncodeStream(b *testing.B) {
+	msgs := buildBatchMsgs(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var buf strings.Builder
+		enc := NewBatchEncoder(&buf)
+		for _, msg := range msgs {
+			enc.Append(msg)
+		}
+		enc.Close()
+	}
+}
+
+func BenchmarkBatchParseUpfront(b *testing.B) {
+	arr := Batch(buildBatchMsgs(10000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		oldParseBatchUpfront(arr)
+	}
+}
+
+func BenchmarkBatchParseStream(b *testing.B) {
+	arr := Batch(buildBatchMsgs(10000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := NewBatchDecoder(strings.NewReader(arr))
+		for {
+			_, err := dec.Next()
+			if err == io.EOF {
+				break
+			}
+		}
+	}
+}
+
+func ExampleBatchEncoder() {
+	var buf strings.Builder
+	enc := NewBatchEncoder(&buf)
+	for i := 1; i <= 2; i++ {
+		msg, _ := Request(i, "update")
+		enc.Append(msg)
+	}
+	enc.Close()
+	fmt.Println(buf.String())
+	// Output: [{"jsonrpc":"2.0","method":"update","id":1},{"jsonrpc":"2.0","method":"update","id":2}]
+}
@@ -0,0 +1,273 @@
+// Package client implements a JSON-RPC 2.0 client with request/response
+// correlation, batch calls, and (over full-duplex transports) server push.
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+)
+
+// ResponseError wraps a server-returned *jsonrpc.ErrorObj as a Go error.
+type ResponseError struct {
+	*jsonrpc.ErrorObj
+}
+
+// Error implements the error interface.
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+// RPCError implements jsonrpc.RPCError, so a ResponseError received from
+// one server can be forwarded verbatim as another's reply, e.g. from a
+// proxying Handler that calls MakeError with the error straight out of
+// a downstream Call.
+func (e *ResponseError) RPCError() *jsonrpc.ErrorObj {
+	return e.ErrorObj
+}
+
+// BatchElem is one call within a BatchCall, mirroring the shape used by
+// go-ethereum's rpc.BatchElem: Args is marshalled as that call's params,
+// and once BatchCall returns, Result holds the decoded value (if Result
+// is a non-nil pointer) or Error holds that element's failure.
+type BatchElem struct {
+	Method string
+	Args   interface{}
+	Result interface{}
+	Error  error
+}
+
+// Client is a JSON-RPC 2.0 client bound to a single full-duplex
+// connection. It assigns monotonically increasing integer ids, matches
+// replies back to the call that made them, and demultiplexes whole batch
+// replies as they arrive. Use NewHTTPClient instead for a plain
+// request/response HTTP transport.
+type Client struct {
+	rwc io.ReadWriteCloser
+
+	idSeq int64
+
+	mu       sync.Mutex
+	pending  map[string]chan *jsonrpc.RPC
+	subs     map[string]*Subscription
+	closed   chan struct{}
+	closeErr error
+}
+
+// NewClient starts reading replies from rwc in the background and
+// returns a Client ready for Call/Notify/BatchCall.
+func NewClient(rwc io.ReadWriteCloser) *Client {
+	c := &Client{
+		rwc:     rwc,
+		pending: make(map[string]chan *jsonrpc.RPC),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// idKey gives the map key used to match a locally generated request id
+// against the *jsonrpc.ID echoed back by the server. id is either the
+// int64 this Client assigned when sending the call, or the *jsonrpc.ID
+// parsed from a reply.
+func idKey(id interface{}) string {
+	switch v := id.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case *jsonrpc.ID:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (c *Client) readLoop() {
+	reader := bufio.NewReader(c.rwc)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			c.shutdown(err)
+			return
+		}
+		msg := strings.TrimRight(line, "\r\n")
+		if msg == "" {
+			continue
+		}
+		req, batch := jsonrpc.Parse(msg)
+		if batch != nil {
+			for _, r := range batch {
+				c.deliver(r)
+			}
+			continue
+		}
+		c.deliver(req)
+	}
+}
+
+// deliver routes a parsed reply to its waiting Call/BatchCall. A
+// server-pushed subscription notification has no id, so it is routed to
+// the matching Subscription by its "subscription" param instead.
+func (c *Client) deliver(r *jsonrpc.RPC) {
+	if r.Type == jsonrpc.NotificationType {
+		c.deliverSubscription(r)
+		return
+	}
+	c.mu.Lock()
+	ch, ok := c.pending[idKey(r.ID)]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	ch <- r
+}
+
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.closed:
+		return
+	default:
+	}
+	c.closeErr = err
+	close(c.closed)
+	for _, ch := range c.pending {
+		close(ch)
+	}
+	c.pending = make(map[string]chan *jsonrpc.RPC)
+}
+
+func (c *Client) send(msg string) error {
+	_, err := io.WriteString(c.rwc, msg+"\n")
+	return err
+}
+
+func (c *Client) register(key string) chan *jsonrpc.RPC {
+	ch := make(chan *jsonrpc.RPC, 1)
+	c.mu.Lock()
+	c.pending[key] = ch
+	c.mu.Unlock()
+	return ch
+}
+
+func (c *Client) unregister(key string) {
+	c.mu.Lock()
+	delete(c.pending, key)
+	c.mu.Unlock()
+}
+
+// Call invokes method with args and decodes the reply into result,
+// blocking until a reply arrives, ctx is done, or the connection closes.
+func (c *Client) Call(ctx context.Context, method string, args interface{}, result interface{}) error {
+	id := atomic.AddInt64(&c.idSeq, 1)
+	msg, rpcErr := jsonrpc.Request(id, method, args)
+	if rpcErr != nil {
+		return fmt.Errorf("jsonrpc/client: %s", rpcErr.Message)
+	}
+	key := idKey(id)
+	ch := c.register(key)
+	defer c.unregister(key)
+
+	if err := c.send(msg); err != nil {
+		return err
+	}
+	select {
+	case reply, ok := <-ch:
+		if !ok {
+			return c.closeErr
+		}
+		return decodeReply(reply, result)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return c.closeErr
+	}
+}
+
+// Notify sends method as a notification; the server does not reply and
+// Notify does not wait for one.
+func (c *Client) Notify(ctx context.Context, method string, args interface{}) error {
+	msg, rpcErr := jsonrpc.Notification(method, args)
+	if rpcErr != nil {
+		return fmt.Errorf("jsonrpc/client: %s", rpcErr.Message)
+	}
+	return c.send(msg)
+}
+
+// BatchCall sends every element of batch as a single JSON-RPC batch
+// request and waits for all of the matching replies, filling in each
+// element's Result or Error in place.
+func (c *Client) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(batch))
+	keys := make([]string, len(batch))
+	chans := make([]chan *jsonrpc.RPC, len(batch))
+	for i, elem := range batch {
+		id := atomic.AddInt64(&c.idSeq, 1)
+		msg, rpcErr := jsonrpc.Request(id, elem.Method, elem.Args)
+		if rpcErr != nil {
+			return fmt.Errorf("jsonrpc/client: %s", rpcErr.Message)
+		}
+		msgs[i] = msg
+		keys[i] = idKey(id)
+		chans[i] = c.register(keys[i])
+	}
+	defer func() {
+		for _, key := range keys {
+			c.unregister(key)
+		}
+	}()
+
+	if err := c.send(jsonrpc.Batch(msgs...)); err != nil {
+		return err
+	}
+	for i := range batch {
+		select {
+		case reply, ok := <-chans[i]:
+			if !ok {
+				batch[i].Error = c.closeErr
+				continue
+			}
+			batch[i].Error = decodeReply(reply, batch[i].Result)
+		case <-ctx.Done():
+			batch[i].Error = ctx.Err()
+		case <-c.closed:
+			batch[i].Error = c.closeErr
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying connection and fails every call still
+// waiting on a reply.
+func (c *Client) Close() error {
+	err := c.rwc.Close()
+	c.shutdown(err)
+	return err
+}
+
+// decodeReply turns a parsed response into result, or a *ResponseError if
+// the server replied with a JSON-RPC error object.
+func decodeReply(reply *jsonrpc.RPC, result interface{}) error {
+	if reply.Error != nil {
+		return &ResponseError{reply.Error}
+	}
+	if result == nil {
+		return nil
+	}
+	data, err := json.Marshal(reply.Result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pipeClient returns a Client wired to one end of an in-memory pipe and the
+// other end, so a test can play the server side by hand: read the request
+// line, decode it, and write back a crafted reply.
+func pipeClient() (*Client, net.Conn) {
+	clientSide, serverSide := net.Pipe()
+	return NewClient(clientSide), serverSide
+}
+
+// readRawLine reads one newline-delimited message off conn.
+func readRawLine(t *testing.T, conn net.Conn) string {
+	t.Helper()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.Nil(t, err)
+	return strings.TrimRight(line, "\n")
+}
+
+// readRequest reads and parses one message, for tests that only deal in
+// single (non-batch) requests.
+func readRequest(t *testing.T, conn net.Conn) *jsonrpc.RPC {
+	t.Helper()
+	req, _ := jsonrpc.Parse(readRawLine(t, conn))
+	return req
+}
+
+func TestClientCall(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+
+	go func() {
+		req := readRequest(t, srv)
+		msg, _ := jsonrpc.Success(req.ID, "pong")
+		srv.Write([]byte(msg + "\n"))
+	}()
+
+	var result string
+	err := c.Call(context.Background(), "ping", nil, &result)
+	assert.Nil(err)
+	assert.Equal("pong", result)
+}
+
+func TestClientCallError(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+
+	go func() {
+		req := readRequest(t, srv)
+		msg, _ := jsonrpc.Error(req.ID, jsonrpc.MethodNotFound())
+		srv.Write([]byte(msg + "\n"))
+	}()
+
+	err := c.Call(context.Background(), "missing", nil, nil)
+	if assert.NotNil(err) {
+		rerr, ok := err.(*ResponseError)
+		if assert.True(ok) {
+			assert.Equal(-32601, rerr.Code)
+		}
+	}
+}
+
+func TestClientCallContextCanceled(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+	defer srv.Close()
+
+	// Drain the request but never reply, so Call is left waiting on ctx.
+	go readRawLine(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := c.Call(ctx, "slow", nil, nil)
+	assert.Equal(context.DeadlineExceeded, err)
+}
+
+func TestClientNotify(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+
+	done := make(chan *jsonrpc.RPC, 1)
+	go func() { done <- readRequest(t, srv) }()
+
+	assert.Nil(c.Notify(context.Background(), "tick", 1))
+	req := <-done
+	assert.Equal(jsonrpc.NotificationType, req.Type)
+	assert.Equal("tick", req.Method)
+}
+
+func TestClientBatchCall(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+
+	go func() {
+		_, batch := jsonrpc.Parse(readRawLine(t, srv))
+		replies := make([]string, len(batch))
+		for i, r := range batch {
+			replies[i], _ = jsonrpc.Success(r.ID, r.Method)
+		}
+		srv.Write([]byte(jsonrpc.Batch(replies...) + "\n"))
+	}()
+
+	var a, b string
+	batch := []BatchElem{
+		{Method: "foo", Result: &a},
+		{Method: "bar", Result: &b},
+	}
+	assert.Nil(c.BatchCall(context.Background(), batch))
+	assert.Nil(batch[0].Error)
+	assert.Equal("foo", a)
+	assert.Nil(batch[1].Error)
+	assert.Equal("bar", b)
+}
+
+func TestClientCloseUnblocksPendingCalls(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Call(context.Background(), "ping", nil, nil)
+	}()
+	readRawLine(t, srv) // wait for the request to land before closing
+	closeErr := c.Close()
+
+	select {
+	case err := <-errCh:
+		assert.Equal(closeErr, err)
+	case <-time.After(time.Second):
+		t.Fatal("Call did not return after Close")
+	}
+}
+
+func TestHTTPClientCall(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(err)
+		req, _ := jsonrpc.Parse(string(body))
+		msg, _ := jsonrpc.Success(req.ID, "pong")
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, msg)
+	}))
+	defer srv.Close()
+
+	c := NewHTTPClient(srv.URL, nil)
+	var result string
+	err := c.Call(context.Background(), "ping", nil, &result)
+	assert.Nil(err)
+	assert.Equal("pong", result)
+}
+
+func TestHTTPClientNotify(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(err)
+		req, _ := jsonrpc.Parse(string(body))
+		assert.Equal(jsonrpc.NotificationType, req.Type)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewHTTPClient(srv.URL, nil)
+	assert.Nil(c.Notify(context.Background(), "tick", 1))
+}
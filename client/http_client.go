@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+)
+
+// HTTPClient is a JSON-RPC client for plain request/response HTTP
+// transports. Unlike Client it keeps no pending-call table or reader
+// goroutine: every Call/BatchCall is one round trip, and Subscribe is not
+// available (see ErrNotificationsUnsupported in the server package).
+type HTTPClient struct {
+	url        string
+	httpClient *http.Client
+	idSeq      int64
+}
+
+// NewHTTPClient returns a client that posts JSON-RPC messages to url. A
+// nil httpClient uses http.DefaultClient.
+func NewHTTPClient(url string, httpClient *http.Client) *HTTPClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &HTTPClient{url: url, httpClient: httpClient}
+}
+
+func (c *HTTPClient) post(ctx context.Context, body string) (string, int, error) {
+	req, err := http.NewRequest(http.MethodPost, c.url, strings.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", resp.StatusCode, err
+	}
+	return string(data), resp.StatusCode, nil
+}
+
+// Call invokes method with args and decodes the reply into result.
+func (c *HTTPClient) Call(ctx context.Context, method string, args interface{}, result interface{}) error {
+	id := atomic.AddInt64(&c.idSeq, 1)
+	msg, rpcErr := jsonrpc.Request(id, method, args)
+	if rpcErr != nil {
+		return fmt.Errorf("jsonrpc/client: %s", rpcErr.Message)
+	}
+	body, status, err := c.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNoContent {
+		return fmt.Errorf("jsonrpc/client: no response for method %q", method)
+	}
+	reply, _ := jsonrpc.Parse(body)
+	return decodeReply(reply, result)
+}
+
+// Notify sends method as a notification over HTTP; the server is expected
+// to reply 204 No Content and Notify discards the body either way.
+func (c *HTTPClient) Notify(ctx context.Context, method string, args interface{}) error {
+	msg, rpcErr := jsonrpc.Notification(method, args)
+	if rpcErr != nil {
+		return fmt.Errorf("jsonrpc/client: %s", rpcErr.Message)
+	}
+	_, _, err := c.post(ctx, msg)
+	return err
+}
+
+// BatchCall posts every element of batch as a single JSON-RPC batch
+// request and fills in each element's Result or Error from the matching
+// reply, matched by id since HTTP batch replies may arrive in any order.
+func (c *HTTPClient) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(batch))
+	keys := make([]string, len(batch))
+	for i, elem := range batch {
+		id := atomic.AddInt64(&c.idSeq, 1)
+		msg, rpcErr := jsonrpc.Request(id, elem.Method, elem.Args)
+		if rpcErr != nil {
+			return fmt.Errorf("jsonrpc/client: %s", rpcErr.Message)
+		}
+		msgs[i] = msg
+		keys[i] = idKey(id)
+	}
+	body, status, err := c.post(ctx, jsonrpc.Batch(msgs...))
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNoContent {
+		for i := range batch {
+			batch[i].Error = fmt.Errorf("jsonrpc/client: no response for method %q", batch[i].Method)
+		}
+		return nil
+	}
+	_, replies := jsonrpc.Parse(body)
+	byID := make(map[string]*jsonrpc.RPC, len(replies))
+	for _, r := range replies {
+		byID[idKey(r.ID)] = r
+	}
+	for i, key := range keys {
+		reply, ok := byID[key]
+		if !ok {
+			batch[i].Error = fmt.Errorf("jsonrpc/client: no response for method %q", batch[i].Method)
+			continue
+		}
+		batch[i].Error = decodeReply(reply, batch[i].Result)
+	}
+	return nil
+}
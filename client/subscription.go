@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+)
+
+// Subscription is returned by Client.Subscribe. Server-pushed events are
+// decoded and sent on the channel passed to Subscribe; Err receives the
+// error that ended the subscription (nil after a clean Unsubscribe) and
+// is closed once no more events will arrive.
+type Subscription struct {
+	client      *Client
+	subID       string
+	unsubMethod string
+	channel     reflect.Value // the chan T passed to Subscribe
+
+	in       chan *jsonrpc.RPC
+	Err      chan error
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// Subscribe calls method (conventionally named "..._subscribe") and
+// arranges for the server's matching push notifications to be decoded
+// and delivered on channel, which must be a writable channel whose
+// element type matches the notification's "result" field. unsubscribeMethod
+// is the call Unsubscribe makes to tear the subscription down server-side.
+func (c *Client) Subscribe(ctx context.Context, method, unsubscribeMethod string, channel interface{}, args ...interface{}) (*Subscription, error) {
+	chVal := reflect.ValueOf(channel)
+	if chVal.Kind() != reflect.Chan || chVal.Type().ChanDir() == reflect.RecvDir {
+		return nil, fmt.Errorf("jsonrpc/client: channel argument must be a writable channel, got %T", channel)
+	}
+	var params interface{}
+	if len(args) > 0 {
+		params = args[0]
+	}
+	var subID string
+	if err := c.Call(ctx, method, params, &subID); err != nil {
+		return nil, err
+	}
+
+	sub := &Subscription{
+		client:      c,
+		subID:       subID,
+		unsubMethod: unsubscribeMethod,
+		channel:     chVal,
+		in:          make(chan *jsonrpc.RPC, 16),
+		Err:         make(chan error, 1),
+		quit:        make(chan struct{}),
+	}
+	c.mu.Lock()
+	if c.subs == nil {
+		c.subs = make(map[string]*Subscription)
+	}
+	c.subs[subID] = sub
+	c.mu.Unlock()
+
+	go sub.run()
+	return sub, nil
+}
+
+// deliverSubscription decodes a server-pushed notification's
+// {"subscription": id, "result": ...} params and forwards it to the
+// matching Subscription, dropping it if no subscription matches or the
+// subscription's buffer is full.
+func (c *Client) deliverSubscription(r *jsonrpc.RPC) {
+	data, err := json.Marshal(r.Params)
+	if err != nil {
+		return
+	}
+	var p struct {
+		Subscription string `json:"subscription"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil || p.Subscription == "" {
+		return
+	}
+	c.mu.Lock()
+	sub, ok := c.subs[p.Subscription]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case sub.in <- r:
+	default:
+	}
+}
+
+func (sub *Subscription) run() {
+	defer close(sub.Err)
+	for {
+		select {
+		case req := <-sub.in:
+			sub.send(req)
+		case <-sub.client.closed:
+			sub.Err <- sub.client.closeErr
+			return
+		case <-sub.quit:
+			return
+		}
+	}
+}
+
+func (sub *Subscription) send(req *jsonrpc.RPC) {
+	data, err := json.Marshal(req.Params)
+	if err != nil {
+		return
+	}
+	var p struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return
+	}
+	val := reflect.New(sub.channel.Type().Elem())
+	if err := json.Unmarshal(p.Result, val.Interface()); err != nil {
+		return
+	}
+	// Race the send against sub.quit so a consumer that stops draining
+	// channel can't wedge this goroutine forever; Unsubscribe closing
+	// quit is what actually lets send (and so run) return.
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: sub.channel, Send: val.Elem()},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(sub.quit)},
+	}
+	reflect.Select(cases)
+}
+
+// Unsubscribe sends the paired unsubscribe call and stops delivering
+// further notifications, draining any already queued in sub's internal
+// buffer. It is safe to call more than once.
+func (sub *Subscription) Unsubscribe() {
+	sub.quitOnce.Do(func() {
+		close(sub.quit)
+		sub.client.mu.Lock()
+		delete(sub.client.subs, sub.subID)
+		sub.client.mu.Unlock()
+
+		var ok bool
+		sub.client.Call(context.Background(), sub.unsubMethod, []string{sub.subID}, &ok)
+
+	drain:
+		for {
+			select {
+			case <-sub.in:
+			default:
+				break drain
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionDeliversEvents(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+
+	go func() {
+		req := readRequest(t, srv)
+		msg, _ := jsonrpc.Success(req.ID, "sub_1")
+		srv.Write([]byte(msg + "\n"))
+		// Give Subscribe time to register the subscription before the
+		// push arrives, so deliverSubscription has someone to find.
+		time.Sleep(20 * time.Millisecond)
+		note, _ := jsonrpc.Notification("foo_subscription", map[string]interface{}{
+			"subscription": "sub_1",
+			"result":       7,
+		})
+		srv.Write([]byte(note + "\n"))
+	}()
+
+	ch := make(chan int, 1)
+	_, err := c.Subscribe(context.Background(), "foo_subscribe", "foo_unsubscribe", ch)
+	assert.Nil(err)
+
+	select {
+	case v := <-ch:
+		assert.Equal(7, v)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+// TestSubscriptionUnsubscribeStopsDelivery guards against Subscription.send
+// wedging its goroutine forever on a consumer that stopped draining its
+// channel: Unsubscribe must still unblock it, not just stop new
+// deliveries, or the goroutine (and sub.Err) never gets cleaned up.
+func TestSubscriptionUnsubscribeStopsDelivery(t *testing.T) {
+	assert := assert.New(t)
+	c, srv := pipeClient()
+	defer c.Close()
+
+	go func() {
+		req := readRequest(t, srv)
+		msg, _ := jsonrpc.Success(req.ID, "sub_1")
+		srv.Write([]byte(msg + "\n"))
+		time.Sleep(20 * time.Millisecond)
+
+		note, _ := jsonrpc.Notification("foo_subscription", map[string]interface{}{
+			"subscription": "sub_1",
+			"result":       1,
+		})
+		srv.Write([]byte(note + "\n"))
+
+		unsubReq := readRequest(t, srv)
+		reply, _ := jsonrpc.Success(unsubReq.ID, true)
+		srv.Write([]byte(reply + "\n"))
+	}()
+
+	ch := make(chan int) // unbuffered and never read, so send would block forever
+	sub, err := c.Subscribe(context.Background(), "foo_subscribe", "foo_unsubscribe", ch)
+	assert.Nil(err)
+
+	// Give sub.run time to pick up the notification and block trying to
+	// deliver it to ch.
+	time.Sleep(50 * time.Millisecond)
+	sub.Unsubscribe()
+
+	select {
+	case <-sub.Err:
+	case <-time.After(time.Second):
+		t.Fatal("sub.run leaked: Err never closed after Unsubscribe")
+	}
+}
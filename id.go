@@ -0,0 +1,147 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// ID represents a JSON-RPC id: a JSON string, an integer-valued number,
+// or null. It keeps the exact bytes it was decoded from, so a reply built
+// from a parsed request echoes the id byte-for-byte instead of, say,
+// turning the string "123" into the number 123 by round-tripping through
+// a Go int.
+type ID struct {
+	raw json.RawMessage
+}
+
+// MarshalJSON implements json.Marshaler, writing the id's original bytes
+// verbatim (or "null" for a nil *ID, so notifications never reach here).
+func (id *ID) MarshalJSON() ([]byte, error) {
+	if id.IsNull() {
+		return []byte("null"), nil
+	}
+	return id.raw, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler by keeping a copy of data,
+// deferring any shape validation to validate.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	id.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// IsNull reports whether the id is absent or JSON null.
+func (id *ID) IsNull() bool {
+	return id == nil || len(id.raw) == 0 || string(id.raw) == "null"
+}
+
+// IsString reports whether the id is a JSON string.
+func (id *ID) IsString() bool {
+	return !id.IsNull() && id.raw[0] == '"'
+}
+
+// IsNumber reports whether the id is a JSON number.
+func (id *ID) IsNumber() bool {
+	return !id.IsNull() && !id.IsString()
+}
+
+// String returns the id's string value: a JSON string id unquoted, or a
+// number id in its original decimal form. It returns "" for a null id.
+func (id *ID) String() string {
+	if id.IsNull() {
+		return ""
+	}
+	if id.IsString() {
+		var s string
+		json.Unmarshal(id.raw, &s)
+		return s
+	}
+	return string(id.raw)
+}
+
+// Int64 returns the id's integer value, or 0 if the id is a string or
+// null. A number written in scientific notation (e.g. 1e1) is valid per
+// validate but isn't a plain base-10 integer literal, so json.Number
+// can't parse it directly; fall back to a float64 parse for those.
+func (id *ID) Int64() int64 {
+	if !id.IsNumber() {
+		return 0
+	}
+	var n json.Number
+	json.Unmarshal(id.raw, &n)
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	var f float64
+	json.Unmarshal(id.raw, &f)
+	return int64(f)
+}
+
+// validate reports whether id holds a shape the JSON-RPC 2.0 spec allows:
+// a string, an integer-valued number, or null. Booleans, fractional
+// numbers, arrays and objects are all invalid ids.
+func (id *ID) validate() *ErrorObj {
+	if id.IsNull() || id.IsString() {
+		return nil
+	}
+	switch string(id.raw) {
+	case "true", "false":
+		return InvalidRequest("id must be a string, number or null")
+	}
+	if c := id.raw[0]; c == '[' || c == '{' {
+		return InvalidRequest("id must be a string, number or null")
+	}
+	var f float64
+	if err := json.Unmarshal(id.raw, &f); err != nil {
+		return InvalidRequest("id must be a string, number or null")
+	}
+	if f != math.Trunc(f) {
+		return InvalidRequest("id must not have a fractional part")
+	}
+	return nil
+}
+
+// newID converts a Go value passed to Request/Success/Error into an *ID,
+// accepting nil, every built-in integer type, json.Number, string and an
+// already-built *ID, and rejecting everything else (notably bool, float
+// with a fractional part, and structured values) with InternalError, just
+// as the id validation this replaces did.
+func newID(v interface{}) (*ID, *ErrorObj) {
+	if v == nil {
+		return nil, nil
+	}
+	switch t := v.(type) {
+	case *ID:
+		return t, nil
+	case string:
+		data, _ := json.Marshal(t)
+		return &ID{raw: data}, nil
+	case json.Number:
+		return newIDFromRaw(json.RawMessage(t))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		data, _ := json.Marshal(t)
+		return &ID{raw: data}, nil
+	case float32:
+		return newIDFromFloat(float64(t))
+	case float64:
+		return newIDFromFloat(t)
+	default:
+		return nil, InternalError()
+	}
+}
+
+func newIDFromFloat(f float64) (*ID, *ErrorObj) {
+	if f != math.Trunc(f) {
+		return nil, InternalError()
+	}
+	data, _ := json.Marshal(int64(f))
+	return &ID{raw: data}, nil
+}
+
+func newIDFromRaw(raw json.RawMessage) (*ID, *ErrorObj) {
+	id := &ID{raw: raw}
+	if err := id.validate(); err != nil {
+		return nil, InternalError()
+	}
+	return id, nil
+}
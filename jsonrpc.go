@@ -1,6 +1,7 @@
 package jsonrpc
 
 import "encoding/json"
+import "io"
 import "strings"
 
 // MsgType ...
@@ -31,9 +32,9 @@ type RPC struct {
 	Params interface{} `json:"params,omitempty"`
 	Result interface{} `json:"result,omitempty"`
 	Error  *ErrorObj   `json:"error,omitempty"`
-	// The request id. This can be of any type. It is used to match the
-	// response with the request that it is replying to.
-	ID interface{} `json:"id,omitempty"`
+	// The request id. It is used to match the response with the request
+	// that it is replying to; see ID for the accepted shapes.
+	ID *ID `json:"id,omitempty"`
 }
 
 // ErrorObj ...
@@ -44,15 +45,17 @@ type ErrorObj struct {
 }
 
 // Request return a JSON-RPC 2.0 request message structures.
-// the id must be {String|Integer|nil} type
+// id must be a string, an integer (any width, signed or unsigned),
+// a float32/float64 with no fractional part, a json.Number, an *ID, or nil.
 func Request(id interface{}, method string, args ...interface{}) (result string, err *ErrorObj) {
-	if err = validateID(id); err != nil {
+	var rpcID *ID
+	if rpcID, err = newID(id); err != nil {
 		return
 	}
 	p := &RPC{
 		Version: jsonRPCVersion,
 		Method:  method,
-		ID:      id,
+		ID:      rpcID,
 	}
 	if len(args) > 0 {
 		p.Params = args[0]
@@ -67,17 +70,13 @@ func Notification(method string, args ...interface{}) (string, *ErrorObj) {
 
 //Batch return a JSON-RPC 2.0 batch message structures.
 func Batch(batch ...string) (arrstr string) {
-	if len(batch) == 0 {
-		return "[]"
+	var buf strings.Builder
+	enc := NewBatchEncoder(&buf)
+	for _, rpc := range batch {
+		enc.Append(rpc)
 	}
-	arrstr = "["
-	for index := 0; index < len(batch)-1; index++ {
-		arrstr += batch[index]
-		arrstr += ","
-	}
-	arrstr += batch[len(batch)-1]
-	arrstr += "]"
-	return
+	enc.Close()
+	return buf.String()
 }
 
 // Success return a JSON-RPC 2.0 success message structures.
@@ -86,26 +85,28 @@ func Success(id interface{}, msg interface{}) (result string, err *ErrorObj) {
 	if msg == nil {
 		return result, InternalError()
 	}
-	if err = validateID(id); err != nil {
+	var rpcID *ID
+	if rpcID, err = newID(id); err != nil {
 		return
 	}
 	p := &RPC{
 		Version: jsonRPCVersion,
 		Result:  msg,
-		ID:      id,
+		ID:      rpcID,
 	}
 	return marshal(p)
 }
 
 //Error return a JSON-RPC 2.0 error message structures.
 func Error(id interface{}, rpcerr *ErrorObj) (result string, err *ErrorObj) {
-	if err = validateID(id); err != nil {
+	var rpcID *ID
+	if rpcID, err = newID(id); err != nil {
 		return
 	}
 	p := &RPC{
 		Version: jsonRPCVersion,
 		Error:   rpcerr,
-		ID:      id,
+		ID:      rpcID,
 	}
 	return marshal(p)
 }
@@ -152,16 +153,18 @@ func InternalError(data ...interface{}) *ErrorObj {
 // Parse return jsonrpc 2.0 message object, ignore the first return value if the msg is batch rpc.
 func Parse(msg string) (req *RPC, batch []*RPC) {
 	if strings.HasPrefix(msg, "[") && strings.HasSuffix(msg, "]") {
-		batch = make([]*RPC, 1)
-		if err := validateMsg(msg, &batch); err == nil {
-			for _, val := range batch {
-				parse(val)
+		batch = []*RPC{}
+		dec := NewBatchDecoder(strings.NewReader(msg))
+		for {
+			r, err := dec.Next()
+			if err == io.EOF {
+				break
 			}
-		} else {
-			req := &RPC{}
-			req.Error = err
-			req.Type = InvalidType
-			batch[0] = req
+			if err != nil {
+				batch = []*RPC{{Error: ParseError(err.Error()), Type: InvalidType}}
+				break
+			}
+			batch = append(batch, r)
 		}
 		return nil, batch
 	}
@@ -180,12 +183,13 @@ func parse(r *RPC) {
 		r.Error = InvalidRequest()
 		return
 	}
-	if err := validateID(r.ID); err != nil {
+	if err := r.ID.validate(); err != nil {
+		r.Type = InvalidType
 		r.Error = err
 		return
 	}
 	if r.Method != "" { //Request
-		if r.ID == nil {
+		if r.ID.IsNull() {
 			r.Type = NotificationType
 		} else {
 			r.Type = RequestType
@@ -202,17 +206,6 @@ func parse(r *RPC) {
 	}
 	return
 }
-func validateID(id interface{}) (err *ErrorObj) {
-	if id != nil {
-		switch id.(type) {
-		case string:
-		case int:
-		default:
-			err = InternalError()
-		}
-	}
-	return
-}
 func validateMsg(msg string, p interface{}) *ErrorObj {
 	if msg == "" {
 		return InvalidRequest()
@@ -0,0 +1,53 @@
+package jsonrpc
+
+import "fmt"
+
+// RPCError is implemented by errors that already carry a JSON-RPC error
+// code and message, so MakeError can use them as-is instead of wrapping
+// them as an opaque internal error.
+type RPCError interface {
+	RPCError() *ErrorObj
+}
+
+// MakeResponse builds the JSON-RPC success reply to r, carrying r's own
+// id in its original byte form so the caller never has to thread req.ID
+// through by hand. Since the spec forbids replying to a notification,
+// calling this on one returns ("", nil).
+func (r *RPC) MakeResponse(result interface{}) (string, *ErrorObj) {
+	if r.Type == NotificationType {
+		return "", nil
+	}
+	return Success(r.ID, result)
+}
+
+// MakeError builds the JSON-RPC error reply to r for err: an *ErrorObj is
+// used verbatim, an error implementing RPCError is unwrapped via
+// RPCError(), nil becomes InternalError() with no data, and anything else
+// is wrapped with InternalError(fmt.Sprint(err)).
+// As with MakeResponse, calling this on a notification returns ("", nil).
+func (r *RPC) MakeError(err interface{}) (string, *ErrorObj) {
+	if r.Type == NotificationType {
+		return "", nil
+	}
+	return Error(r.ID, errorObjFrom(err))
+}
+
+// MakeErrorf is a convenience for MakeError(ErrorWith(code, fmt.Sprintf(format, args...))).
+func (r *RPC) MakeErrorf(code int, format string, args ...interface{}) (string, *ErrorObj) {
+	return r.MakeError(ErrorWith(code, fmt.Sprintf(format, args...)))
+}
+
+func errorObjFrom(err interface{}) *ErrorObj {
+	switch e := err.(type) {
+	case nil:
+		return InternalError()
+	case *ErrorObj:
+		return e
+	case RPCError:
+		return e.RPCError()
+	case error:
+		return InternalError(e.Error())
+	default:
+		return InternalError(fmt.Sprint(e))
+	}
+}
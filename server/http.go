@@ -0,0 +1,56 @@
+package server
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+)
+
+// HTTPHandler adapts a Handler to net/http. It accepts a single JSON-RPC
+// object or a batch array in the request body and writes back whatever
+// non-notification replies result: a single object when the request
+// wasn't a batch, a batch array when it was, and 204 No Content when
+// there is nothing to report, e.g. a lone notification or a batch made
+// up entirely of notifications, per the spec.
+type HTTPHandler struct {
+	Handler Handler
+}
+
+// NewHTTPHandler wraps h as an http.Handler.
+func NewHTTPHandler(h Handler) *HTTPHandler {
+	return &HTTPHandler{Handler: h}
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var mu sync.Mutex
+	var replies []string
+	conn := NewConn(func(msg string) error {
+		mu.Lock()
+		replies = append(replies, msg)
+		mu.Unlock()
+		return nil
+	})
+	conn.pushUnsupported = true
+	Dispatch(r.Context(), h.Handler, conn, string(body))
+
+	if len(replies) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		io.WriteString(w, jsonrpc.Batch(replies...))
+		return
+	}
+	io.WriteString(w, replies[0])
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+)
+
+var (
+	ctxType      = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorObjType = reflect.TypeOf((*jsonrpc.ErrorObj)(nil))
+)
+
+// HandlerMux is a Handler that routes by method name to plain Go functions
+// registered with Register, decoding request params into each function's
+// argument type via reflection.
+type HandlerMux struct {
+	mu       sync.RWMutex
+	handlers map[string]reflect.Value
+	argTypes map[string]reflect.Type
+}
+
+// NewHandlerMux returns an empty HandlerMux.
+func NewHandlerMux() *HandlerMux {
+	return &HandlerMux{
+		handlers: make(map[string]reflect.Value),
+		argTypes: make(map[string]reflect.Type),
+	}
+}
+
+// Register associates method with fn, which must have the signature
+//
+//	func(ctx context.Context, params *T) (result interface{}, rpcErr *jsonrpc.ErrorObj)
+//
+// for some struct type T. Register panics on a malformed fn, since
+// registration happens once at startup and any mistake is a programmer
+// error that should fail fast.
+func (m *HandlerMux) Register(method string, fn interface{}) {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.NumOut() != 2 ||
+		!t.In(0).Implements(ctxType) || t.In(1).Kind() != reflect.Ptr || t.Out(1) != errorObjType {
+		panic(fmt.Sprintf("jsonrpc/server: Register(%q): fn must be func(context.Context, *T) (interface{}, *jsonrpc.ErrorObj)", method))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[method] = v
+	m.argTypes[method] = t.In(1).Elem()
+}
+
+// Handle implements Handler by looking up req.Method, decoding req.Params
+// into the registered argument type and invoking the handler.
+func (m *HandlerMux) Handle(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+	m.mu.RLock()
+	fn, ok := m.handlers[req.Method]
+	argType := m.argTypes[req.Method]
+	m.mu.RUnlock()
+	if !ok {
+		conn.Reply(req, nil, jsonrpc.MethodNotFound())
+		return
+	}
+	arg := reflect.New(argType)
+	if req.Params != nil {
+		data, err := json.Marshal(req.Params)
+		if err != nil {
+			conn.Reply(req, nil, jsonrpc.InvalidParams(err.Error()))
+			return
+		}
+		if err := json.Unmarshal(data, arg.Interface()); err != nil {
+			conn.Reply(req, nil, jsonrpc.InvalidParams(err.Error()))
+			return
+		}
+	}
+	out := fn.Call([]reflect.Value{reflect.ValueOf(ctx), arg})
+	var rpcErr *jsonrpc.ErrorObj
+	if errv := out[1]; !errv.IsNil() {
+		rpcErr = errv.Interface().(*jsonrpc.ErrorObj)
+	}
+	conn.Reply(req, out[0].Interface(), rpcErr)
+}
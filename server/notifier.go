@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNotificationsUnsupported is returned by Notifier.Notify and Conn.Notify when the
+// underlying transport cannot push unsolicited messages. Only full-duplex
+// transports (ServeStream) support it; HTTPHandler does not, since a
+// response can only ever be written once for a given request.
+var ErrNotificationsUnsupported = errors.New("jsonrpc/server: notifications are not supported on this transport")
+
+type notifierKey struct{}
+
+// Notifier lets a registered "..._subscribe" method create a subscription
+// id and later push events to it outside of the request/response cycle.
+// Retrieve the one bound to the current call with NotifierFromContext.
+type Notifier struct {
+	conn   *Conn
+	method string
+}
+
+// NotifierFromContext returns the Notifier Dispatch attached to ctx, or
+// nil if called outside of a Handler invocation.
+func NotifierFromContext(ctx context.Context) *Notifier {
+	n, _ := ctx.Value(notifierKey{}).(*Notifier)
+	return n
+}
+
+// NewSubscriptionID returns a fresh, connection-unique subscription id, to
+// be returned as the result of the "..._subscribe" call.
+func (n *Notifier) NewSubscriptionID() string {
+	id := atomic.AddInt64(&n.conn.subIDSeq, 1)
+	return fmt.Sprintf("sub_%d", id)
+}
+
+// Notify pushes payload to subscription id as a top-level JSON-RPC
+// notification shaped like eth_subscribe's: method is the subscribing
+// call's method with its "_subscribe" suffix replaced by "_subscription",
+// and params is {"subscription": id, "result": payload}.
+func (n *Notifier) Notify(id string, payload interface{}) error {
+	if n.conn.pushUnsupported {
+		return ErrNotificationsUnsupported
+	}
+	return n.conn.Notify(n.method, &subscriptionParams{Subscription: id, Result: payload})
+}
+
+type subscriptionParams struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+func subscriptionMethodFor(method string) string {
+	if strings.HasSuffix(method, "_subscribe") {
+		return strings.TrimSuffix(method, "_subscribe") + "_subscription"
+	}
+	return method + "_subscription"
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionMethodFor(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("foo_subscription", subscriptionMethodFor("foo_subscribe"))
+	assert.Equal("eth_subscription", subscriptionMethodFor("eth_subscribe"))
+	// "_subscribe" appearing anywhere but the end must not be treated as
+	// the suffix, or part of the method name silently gets dropped.
+	assert.Equal("foo_subscribeOnce_subscription", subscriptionMethodFor("foo_subscribeOnce"))
+	assert.Equal("plain_subscription", subscriptionMethodFor("plain"))
+}
+
+func TestNotifierFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	var notifier *Notifier
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		notifier = NotifierFromContext(ctx)
+		conn.Reply(req, notifier.NewSubscriptionID(), nil)
+	})
+
+	var pushed []string
+	conn := NewConn(func(msg string) error {
+		pushed = append(pushed, msg)
+		return nil
+	})
+	Dispatch(context.Background(), h, conn, `{"jsonrpc":"2.0","method":"foo_subscribe","id":1}`)
+
+	if assert.NotNil(notifier) {
+		assert.Nil(notifier.Notify("sub_1", 42))
+		if assert.Equal(2, len(pushed)) {
+			assert.Contains(pushed[1], `"method":"foo_subscription"`)
+			assert.Contains(pushed[1], `"subscription":"sub_1"`)
+		}
+	}
+}
+
+func TestNotifierUnsupportedTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := NewConn(func(msg string) error { return nil })
+	conn.pushUnsupported = true
+	notifier := &Notifier{conn: conn, method: "foo_subscription"}
+
+	assert.Equal(ErrNotificationsUnsupported, notifier.Notify("sub_1", nil))
+}
+
+func TestNewSubscriptionIDUnique(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := NewConn(func(msg string) error { return nil })
+	notifier := &Notifier{conn: conn}
+	assert.NotEqual(notifier.NewSubscriptionID(), notifier.NewSubscriptionID())
+}
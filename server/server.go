@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+)
+
+// Handler handles a single JSON-RPC request or notification received on
+// a connection. Implementations reply (unless req is a notification) by
+// calling conn.Reply, and may push out-of-band notifications by calling
+// conn.Notify.
+type Handler interface {
+	Handle(ctx context.Context, conn *Conn, req *jsonrpc.RPC)
+}
+
+// HandlerFunc adapts a plain function to the Handler interface.
+type HandlerFunc func(ctx context.Context, conn *Conn, req *jsonrpc.RPC)
+
+// Handle calls f(ctx, conn, req).
+func (f HandlerFunc) Handle(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+	f(ctx, conn, req)
+}
+
+// Conn is a transport-agnostic JSON-RPC connection. It keeps track of the
+// requests that are currently in flight and provides Reply/Notify so a
+// Handler never has to touch the transport directly.
+type Conn struct {
+	send func(msg string) error
+
+	// pushUnsupported marks a Conn built over a transport that can't
+	// deliver unsolicited messages (plain request/response HTTP), so
+	// Notify and Notifier.Notify can fail fast with ErrNotificationsUnsupported.
+	pushUnsupported bool
+	subIDSeq        int64
+
+	mu      sync.Mutex
+	pending map[string]*jsonrpc.RPC
+}
+
+// NewConn wraps send, the function used to write one complete JSON-RPC
+// message to the underlying transport, as a Conn.
+func NewConn(send func(msg string) error) *Conn {
+	return &Conn{send: send, pending: make(map[string]*jsonrpc.RPC)}
+}
+
+func idKey(id *jsonrpc.ID) string {
+	return id.String()
+}
+
+func (c *Conn) track(req *jsonrpc.RPC) {
+	if req.Type != jsonrpc.RequestType {
+		return
+	}
+	c.mu.Lock()
+	c.pending[idKey(req.ID)] = req
+	c.mu.Unlock()
+}
+
+func (c *Conn) untrack(req *jsonrpc.RPC) {
+	c.mu.Lock()
+	delete(c.pending, idKey(req.ID))
+	c.mu.Unlock()
+}
+
+// Reply sends a response for req built from result or rpcErr (rpcErr wins
+// if both are given), and is a no-op for notifications, since the spec
+// forbids replying to those.
+func (c *Conn) Reply(req *jsonrpc.RPC, result interface{}, rpcErr *jsonrpc.ErrorObj) error {
+	defer c.untrack(req)
+	if req.Type == jsonrpc.NotificationType {
+		return nil
+	}
+	var msg string
+	if rpcErr != nil {
+		msg, _ = req.MakeError(rpcErr)
+	} else {
+		var err *jsonrpc.ErrorObj
+		if msg, err = req.MakeResponse(result); err != nil {
+			msg, _ = req.MakeError(err)
+		}
+	}
+	return c.send(msg)
+}
+
+// Notify writes method as a server-initiated notification, outside of any
+// request/response cycle. Transports that cannot push unsolicited
+// messages (HTTP) reject this; see ErrNotificationsUnsupported.
+func (c *Conn) Notify(method string, params interface{}) error {
+	if c.pushUnsupported {
+		return ErrNotificationsUnsupported
+	}
+	msg, err := jsonrpc.Notification(method, params)
+	if err != nil {
+		return fmt.Errorf("jsonrpc/server: %s", err.Message)
+	}
+	return c.send(msg)
+}
+
+// Dispatch parses msg and routes every request/notification it contains
+// to h, using conn to reply. Batch arrays are fanned out concurrently, one
+// goroutine per element, matching the "MAY be processed in parallel" batch
+// semantics from the JSON-RPC 2.0 spec.
+func Dispatch(ctx context.Context, h Handler, conn *Conn, msg string) {
+	req, batch := jsonrpc.Parse(msg)
+	if batch == nil {
+		dispatchOne(ctx, h, conn, req)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, r := range batch {
+		wg.Add(1)
+		go func(r *jsonrpc.RPC) {
+			defer wg.Done()
+			dispatchOne(ctx, h, conn, r)
+		}(r)
+	}
+	wg.Wait()
+}
+
+func dispatchOne(ctx context.Context, h Handler, conn *Conn, req *jsonrpc.RPC) {
+	if req.Type == jsonrpc.InvalidType {
+		conn.Reply(req, nil, req.Error)
+		return
+	}
+	conn.track(req)
+	ctx = context.WithValue(ctx, notifierKey{}, &Notifier{conn: conn, method: subscriptionMethodFor(req.Method)})
+	h.Handle(ctx, conn, req)
+}
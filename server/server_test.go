@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collectConn returns a Conn whose sent messages are appended to *sent, for
+// tests that just need to inspect what a Handler wrote back.
+func collectConn(sent *[]string) *Conn {
+	var mu sync.Mutex
+	return NewConn(func(msg string) error {
+		mu.Lock()
+		*sent = append(*sent, msg)
+		mu.Unlock()
+		return nil
+	})
+}
+
+func TestDispatchSingle(t *testing.T) {
+	assert := assert.New(t)
+
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		conn.Reply(req, "ok", nil)
+	})
+
+	var sent []string
+	Dispatch(context.Background(), h, collectConn(&sent), `{"jsonrpc":"2.0","method":"update","id":1}`)
+	if assert.Equal(1, len(sent)) {
+		assert.Equal(`{"jsonrpc":"2.0","result":"ok","id":1}`, sent[0])
+	}
+
+	sent = nil
+	Dispatch(context.Background(), h, collectConn(&sent), `{"jsonrpc":"2.0","method":"update"}`)
+	assert.Equal(0, len(sent), "a notification must not be replied to")
+}
+
+func TestDispatchInvalidRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	called := false
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		called = true
+	})
+
+	var sent []string
+	Dispatch(context.Background(), h, collectConn(&sent), `{"jsonrpc":"2.0","method":"update","id":[1]}`)
+	assert.False(called, "an invalid request must not reach the Handler")
+	if assert.Equal(1, len(sent)) {
+		assert.Contains(sent[0], `"code":-32600`)
+	}
+}
+
+func TestDispatchEmptyBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	called := false
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		called = true
+	})
+
+	var sent []string
+	assert.NotPanics(func() {
+		Dispatch(context.Background(), h, collectConn(&sent), "[]")
+	})
+	assert.False(called)
+	assert.Equal(0, len(sent))
+}
+
+func TestDispatchBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		conn.Reply(req, req.Method, nil)
+	})
+
+	var sent []string
+	arr := `[
+		{"jsonrpc": "2.0", "method": "sum", "id": "1"},
+		{"jsonrpc": "2.0", "method": "notify_hello"},
+		{"jsonrpc": "2.0", "method": "subtract", "id": "2"}
+	]`
+	Dispatch(context.Background(), h, collectConn(&sent), arr)
+	assert.Equal(2, len(sent), "the lone notification must not produce a reply")
+}
+
+func TestHandlerMux(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	mux := NewHandlerMux()
+	mux.Register("sum", func(ctx context.Context, p *args) (interface{}, *jsonrpc.ErrorObj) {
+		return p.A + p.B, nil
+	})
+
+	var sent []string
+	Dispatch(context.Background(), mux, collectConn(&sent), `{"jsonrpc":"2.0","method":"sum","params":{"a":1,"b":2},"id":1}`)
+	if assert.Equal(1, len(sent)) {
+		assert.Equal(`{"jsonrpc":"2.0","result":3,"id":1}`, sent[0])
+	}
+
+	sent = nil
+	Dispatch(context.Background(), mux, collectConn(&sent), `{"jsonrpc":"2.0","method":"missing","id":1}`)
+	if assert.Equal(1, len(sent)) {
+		assert.Contains(sent[0], `"code":-32601`)
+	}
+
+	sent = nil
+	Dispatch(context.Background(), mux, collectConn(&sent), `{"jsonrpc":"2.0","method":"sum","params":[1,2],"id":1}`)
+	if assert.Equal(1, len(sent)) {
+		assert.Contains(sent[0], `"code":-32602`)
+	}
+}
+
+func TestHandlerMuxRegisterPanics(t *testing.T) {
+	assert := assert.New(t)
+
+	mux := NewHandlerMux()
+	assert.Panics(func() {
+		mux.Register("bad", func(int, int) {})
+	})
+}
+
+func TestHTTPHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHTTPHandler(HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		conn.Reply(req, "ok", nil)
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"update","id":1}`))
+	assert.Nil(err)
+	defer resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"update"}`))
+	assert.Nil(err)
+	defer resp.Body.Close()
+	assert.Equal(http.StatusNoContent, resp.StatusCode)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Framing selects how messages are delimited on a stream transport.
+type Framing int
+
+const (
+	// NewlineFraming delimits messages with a single '\n'. Simplest
+	// option, and fine as long as messages can't contain a raw newline.
+	NewlineFraming Framing = iota
+	// LengthPrefixedFraming prefixes each message with a big-endian
+	// uint32 byte length, so message content is unconstrained.
+	LengthPrefixedFraming
+)
+
+// ServeStream serves a single full-duplex connection over rwc until it is
+// closed or ctx is cancelled, dispatching every framed message it reads
+// to h. The Conn passed to h stays valid for the life of the connection,
+// so handlers may hold onto it to push notifications (see Conn.Notify)
+// after their triggering request has already been replied to.
+func ServeStream(ctx context.Context, h Handler, rwc io.ReadWriteCloser, framing Framing) error {
+	defer rwc.Close()
+
+	var sendMu sync.Mutex
+	conn := NewConn(func(msg string) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return writeFramed(rwc, framing, msg)
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			rwc.Close()
+		case <-done:
+		}
+	}()
+
+	reader := bufio.NewReader(rwc)
+	for {
+		msg, err := readFramed(reader, framing)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		go Dispatch(ctx, h, conn, msg)
+	}
+}
+
+func readFramed(r *bufio.Reader, framing Framing) (string, error) {
+	if framing == LengthPrefixedFraming {
+		var n uint32
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return "", err
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeFramed(w io.Writer, framing Framing, msg string) error {
+	if framing == LengthPrefixedFraming {
+		if err := binary.Write(w, binary.BigEndian, uint32(len(msg))); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, msg)
+		return err
+	}
+	_, err := io.WriteString(w, msg+"\n")
+	return err
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	jsonrpc "github.com/teambition/jsonrpc-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeStreamNewlineFraming(t *testing.T) {
+	assert := assert.New(t)
+	client, srv := net.Pipe()
+
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		conn.Reply(req, "pong", nil)
+	})
+	done := make(chan error, 1)
+	go func() { done <- ServeStream(context.Background(), h, srv, NewlineFraming) }()
+
+	_, err := client.Write([]byte(`{"jsonrpc":"2.0","method":"ping","id":1}` + "\n"))
+	assert.Nil(err)
+
+	reply, err := bufio.NewReader(client).ReadString('\n')
+	assert.Nil(err)
+	assert.Equal(`{"jsonrpc":"2.0","result":"pong","id":1}`+"\n", reply)
+
+	client.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeStream did not return after the connection closed")
+	}
+}
+
+func TestServeStreamLengthPrefixedFraming(t *testing.T) {
+	assert := assert.New(t)
+	client, srv := net.Pipe()
+
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {
+		conn.Reply(req, "pong", nil)
+	})
+	done := make(chan error, 1)
+	go func() { done <- ServeStream(context.Background(), h, srv, LengthPrefixedFraming) }()
+
+	req := `{"jsonrpc":"2.0","method":"ping","id":1}`
+	assert.Nil(binary.Write(client, binary.BigEndian, uint32(len(req))))
+	_, err := io.WriteString(client, req)
+	assert.Nil(err)
+
+	reader := bufio.NewReader(client)
+	var n uint32
+	assert.Nil(binary.Read(reader, binary.BigEndian, &n))
+	buf := make([]byte, n)
+	_, err = io.ReadFull(reader, buf)
+	assert.Nil(err)
+	assert.Equal(`{"jsonrpc":"2.0","result":"pong","id":1}`, string(buf))
+
+	client.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeStream did not return after the connection closed")
+	}
+}
+
+func TestServeStreamCtxCancelClosesConn(t *testing.T) {
+	assert := assert.New(t)
+	client, srv := net.Pipe()
+
+	h := HandlerFunc(func(ctx context.Context, conn *Conn, req *jsonrpc.RPC) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ServeStream(ctx, h, srv, NewlineFraming) }()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeStream did not return after ctx cancellation")
+	}
+
+	// With the server's end closed, a write from the client side must
+	// now fail instead of blocking forever waiting for a reader.
+	_, err := client.Write([]byte("ping\n"))
+	assert.NotNil(err)
+}